@@ -0,0 +1,260 @@
+package dbsql
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os/exec"
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/databricks/databricks-sql-go/internal/config"
+	"github.com/pkg/errors"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/clientcredentials"
+)
+
+// Authenticator supplies the bearer token used on every Thrift-over-HTTP
+// request. Token is called before each request and whenever the cached
+// token is near expiry; implementations are expected to do their own
+// caching only if they can do better than the default near-expiry refresh
+// applied by cachingAuthenticator.
+type Authenticator interface {
+	Token(ctx context.Context) (token string, expiry time.Time, err error)
+}
+
+// tokenRefreshSkew is how far ahead of the reported expiry a cached token is
+// treated as stale, so a request is never built with a token that expires
+// mid-flight.
+const tokenRefreshSkew = 30 * time.Second
+
+// cachingAuthenticator wraps an Authenticator and only calls through to it
+// once the cached token is within tokenRefreshSkew of expiring.
+type cachingAuthenticator struct {
+	inner Authenticator
+
+	mu     sync.Mutex
+	token  string
+	expiry time.Time
+}
+
+func (c *cachingAuthenticator) Token(ctx context.Context) (string, time.Time, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.token != "" && time.Until(c.expiry) > tokenRefreshSkew {
+		return c.token, c.expiry, nil
+	}
+	token, expiry, err := c.inner.Token(ctx)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	c.token, c.expiry = token, expiry
+	return token, expiry, nil
+}
+
+func cached(a Authenticator) Authenticator {
+	return &cachingAuthenticator{inner: a}
+}
+
+// tokenSourceAuthenticator adapts an oauth2.TokenSource, which already does
+// its own caching, to the Authenticator interface.
+type tokenSourceAuthenticator struct {
+	src oauth2.TokenSource
+}
+
+func (a tokenSourceAuthenticator) Token(ctx context.Context) (string, time.Time, error) {
+	tok, err := a.src.Token()
+	if err != nil {
+		return "", time.Time{}, wrapErr(err, "error fetching token from oauth2.TokenSource")
+	}
+	return tok.AccessToken, tok.Expiry, nil
+}
+
+// WithTokenSource authenticates using a caller-supplied oauth2.TokenSource,
+// e.g. one backed by a refresh token or an external token cache.
+func WithTokenSource(src oauth2.TokenSource) connOption {
+	return func(c *config.Config) {
+		c.Authenticator = tokenSourceAuthenticator{src: src}
+	}
+}
+
+// WithOAuthClientCredentials authenticates as a Databricks service principal
+// using the OAuth 2.0 client credentials grant.
+func WithOAuthClientCredentials(clientID, clientSecret, tokenURL string, scopes []string) connOption {
+	return func(c *config.Config) {
+		cc := &clientcredentials.Config{
+			ClientID:     clientID,
+			ClientSecret: clientSecret,
+			TokenURL:     tokenURL,
+			Scopes:       scopes,
+		}
+		c.Authenticator = cached(tokenSourceAuthenticator{src: cc.TokenSource(context.Background())})
+	}
+}
+
+// WithAzureAD authenticates using an Azure AD service principal's client
+// credentials against the tenant's v2.0 token endpoint, for Databricks
+// workspaces that require Azure AD tokens rather than Databricks OAuth.
+func WithAzureAD(tenantID, clientID, clientSecret string) connOption {
+	return func(c *config.Config) {
+		cc := &clientcredentials.Config{
+			ClientID:     clientID,
+			ClientSecret: clientSecret,
+			TokenURL:     fmt.Sprintf("https://login.microsoftonline.com/%s/oauth2/v2.0/token", tenantID),
+			Scopes:       []string{"2ff814a6-3304-4ab8-85cb-cd0e6f879c1d/.default"},
+		}
+		c.Authenticator = cached(tokenSourceAuthenticator{src: cc.TokenSource(context.Background())})
+	}
+}
+
+// WithOAuthU2M authenticates as a human user via the OAuth 2.0 authorization
+// code flow with PKCE against workspaceHost's OIDC endpoints, opening a
+// local callback listener to receive the redirect. The authorize URL is
+// printed and, best-effort, opened in the user's default browser. Intended
+// for interactive/CLI use, not for long-lived services.
+func WithOAuthU2M(workspaceHost, clientID, redirectURL string) connOption {
+	return func(c *config.Config) {
+		c.Authenticator = cached(&u2mAuthenticator{
+			workspaceHost: workspaceHost,
+			clientID:      clientID,
+			redirectURL:   redirectURL,
+		})
+	}
+}
+
+// u2mAuthenticator runs the interactive authorization-code-with-PKCE flow on
+// every Token call. Wrapped by cachingAuthenticator, that is the first call
+// and each call after the cached token comes within tokenRefreshSkew of
+// expiring; the refresh token is not used.
+type u2mAuthenticator struct {
+	workspaceHost string
+	clientID      string
+	redirectURL   string
+
+	mu sync.Mutex
+}
+
+func (a *u2mAuthenticator) Token(ctx context.Context) (string, time.Time, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	verifier := oauth2.GenerateVerifier()
+	oauthCfg := &oauth2.Config{
+		ClientID:    a.clientID,
+		RedirectURL: a.redirectURL,
+		Endpoint: oauth2.Endpoint{
+			AuthURL:  fmt.Sprintf("https://%s/oidc/v1/authorize", a.workspaceHost),
+			TokenURL: fmt.Sprintf("https://%s/oidc/v1/token", a.workspaceHost),
+		},
+		Scopes: []string{"all-apis", "offline_access"},
+	}
+
+	code, err := a.runAuthorizationCodeFlow(ctx, oauthCfg, verifier)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	tok, err := oauthCfg.Exchange(ctx, code, oauth2.VerifierOption(verifier))
+	if err != nil {
+		return "", time.Time{}, wrapErr(err, "error exchanging authorization code")
+	}
+	return tok.AccessToken, tok.Expiry, nil
+}
+
+// runAuthorizationCodeFlow opens a listener on the redirect URL's port,
+// directs the user to the workspace's authorize endpoint (printing the URL
+// and attempting to open it in a browser), and blocks until the redirect
+// delivers the authorization code (or ctx is done).
+func (a *u2mAuthenticator) runAuthorizationCodeFlow(ctx context.Context, oauthCfg *oauth2.Config, verifier string) (string, error) {
+	state, err := randomState()
+	if err != nil {
+		return "", wrapErr(err, "error generating oauth state")
+	}
+
+	codeCh := make(chan string, 1)
+	errCh := make(chan error, 1)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		if errParam := r.URL.Query().Get("error"); errParam != "" {
+			errCh <- fmt.Errorf("databricks: oauth authorization failed: %s", errParam)
+			return
+		}
+		if r.URL.Query().Get("state") != state {
+			errCh <- errors.New("databricks: oauth callback state mismatch")
+			return
+		}
+		codeCh <- r.URL.Query().Get("code")
+		fmt.Fprint(w, "Authentication complete, you may close this window.")
+	})
+
+	addr, err := redirectURLAddr(a.redirectURL)
+	if err != nil {
+		return "", wrapErr(err, "error parsing redirect url")
+	}
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return "", wrapErr(err, "error starting oauth callback listener")
+	}
+	srv := &http.Server{Handler: mux}
+	go srv.Serve(ln) //nolint:errcheck // Serve returns http.ErrServerClosed once Shutdown below runs
+	defer srv.Shutdown(context.Background())
+
+	authURL := oauthCfg.AuthCodeURL(state, oauth2.S256ChallengeOption(verifier))
+	fmt.Printf("databricks: open the following URL to authenticate:\n%s\n", authURL)
+	openBrowser(authURL)
+
+	select {
+	case code := <-codeCh:
+		return code, nil
+	case err := <-errCh:
+		return "", err
+	case <-ctx.Done():
+		return "", ctx.Err()
+	}
+}
+
+// redirectURLAddr extracts the listen address (e.g. "127.0.0.1:8020") from a
+// loopback redirect URL such as http://localhost:8020/callback. It always
+// binds 127.0.0.1, never all interfaces, so the authorization code can't be
+// raced by another host on the network while the listener is up.
+func redirectURLAddr(redirectURL string) (string, error) {
+	u, err := url.Parse(redirectURL)
+	if err != nil {
+		return "", err
+	}
+	if u.Port() == "" {
+		return "", fmt.Errorf("databricks: redirect url %q must include a port", redirectURL)
+	}
+	return "127.0.0.1:" + u.Port(), nil
+}
+
+func randomState() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// openBrowser best-effort opens url in the user's default browser. Failures
+// are ignored since the URL is always printed as a fallback.
+func openBrowser(url string) {
+	var cmd string
+	var args []string
+	switch runtime.GOOS {
+	case "darwin":
+		cmd, args = "open", []string{url}
+	case "windows":
+		cmd, args = "rundll32", []string{"url.dll,FileProtocolHandler", url}
+	default:
+		cmd, args = "xdg-open", []string{url}
+	}
+	_ = exec.Command(cmd, args...).Start()
+}
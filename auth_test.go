@@ -0,0 +1,89 @@
+package dbsql
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+type fakeAuthenticator struct {
+	calls  int
+	token  string
+	expiry time.Time
+}
+
+func (f *fakeAuthenticator) Token(ctx context.Context) (string, time.Time, error) {
+	f.calls++
+	return f.token, f.expiry, nil
+}
+
+func TestCachingAuthenticatorReusesUnexpiredToken(t *testing.T) {
+	inner := &fakeAuthenticator{token: "tok-1", expiry: time.Now().Add(time.Hour)}
+	c := cached(inner)
+
+	for i := 0; i < 3; i++ {
+		token, _, err := c.Token(context.Background())
+		if err != nil {
+			t.Fatalf("Token() returned error: %v", err)
+		}
+		if token != "tok-1" {
+			t.Errorf("Token() = %q, want %q", token, "tok-1")
+		}
+	}
+	if inner.calls != 1 {
+		t.Errorf("expected the inner authenticator to be called once, got %d calls", inner.calls)
+	}
+}
+
+func TestCachingAuthenticatorRefreshesNearExpiry(t *testing.T) {
+	inner := &fakeAuthenticator{token: "tok-1", expiry: time.Now().Add(tokenRefreshSkew / 2)}
+	c := cached(inner)
+
+	token, _, err := c.Token(context.Background())
+	if err != nil {
+		t.Fatalf("Token() returned error: %v", err)
+	}
+	if token != "tok-1" || inner.calls != 1 {
+		t.Fatalf("unexpected first call result: token=%q calls=%d", token, inner.calls)
+	}
+
+	inner.token = "tok-2"
+	inner.expiry = time.Now().Add(time.Hour)
+	token, _, err = c.Token(context.Background())
+	if err != nil {
+		t.Fatalf("Token() returned error: %v", err)
+	}
+	if token != "tok-2" {
+		t.Errorf("Token() = %q, want refreshed token %q", token, "tok-2")
+	}
+	if inner.calls != 2 {
+		t.Errorf("expected the inner authenticator to be called again once within the refresh skew, got %d calls", inner.calls)
+	}
+}
+
+func TestRedirectURLAddr(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    string
+		wantErr bool
+	}{
+		{"http://localhost:8020/callback", "127.0.0.1:8020", false},
+		{"http://localhost/callback", "", true},
+		{"://not-a-url", "", true},
+	}
+	for _, tt := range tests {
+		got, err := redirectURLAddr(tt.in)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("redirectURLAddr(%q): expected an error, got nil", tt.in)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("redirectURLAddr(%q) returned error: %v", tt.in, err)
+		}
+		if got != tt.want {
+			t.Errorf("redirectURLAddr(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
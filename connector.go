@@ -3,7 +3,9 @@ package dbsql
 import (
 	"context"
 	"database/sql/driver"
-	"fmt"
+	"io"
+	"net/http"
+	"sync"
 
 	"github.com/databricks/databricks-sql-go/driverctx"
 	"github.com/databricks/databricks-sql-go/internal/cli_service"
@@ -16,11 +18,34 @@ import (
 
 type connector struct {
 	cfg *config.Config
+
+	transportOnce sync.Once
+	transport     http.RoundTripper
 }
 
-func (c *connector) Connect(ctx context.Context) (driver.Conn, error) {
+// httpTransport lazily builds (and memoizes) the RoundTripper used for every
+// Thrift request from this connector, so repeated Connect calls from a
+// connection pool don't re-wrap an already-wrapped transport. The write to
+// c.cfg.HTTPTransport happens inside the sync.Once so concurrent Connect
+// calls (database/sql pools connections concurrently) never race on it.
+func (c *connector) httpTransport() http.RoundTripper {
+	c.transportOnce.Do(func() {
+		c.transport = buildHTTPTransport(c.cfg)
+		c.cfg.HTTPTransport = c.transport
+	})
+	return c.transport
+}
 
-	tclient, err := client.InitThriftClient(c.cfg)
+func (c *connector) Connect(ctx context.Context) (driver.Conn, error) {
+	retry := retryPolicyFromConfig(c.cfg)
+	c.httpTransport()
+
+	var tclient cli_service.TCLIService
+	err := retry.do(ctx, true, func() error {
+		var initErr error
+		tclient, initErr = client.InitThriftClient(c.cfg)
+		return initErr
+	})
 	if err != nil {
 		return nil, wrapErr(err, "error initializing thrift client")
 	}
@@ -47,8 +72,13 @@ func (c *connector) Connect(ctx context.Context) (driver.Conn, error) {
 			})
 		},
 	}
-	// default timeout in here in addition to potential context timeout
-	_, res, err := sentinel.Watch(ctx, c.cfg.PollInterval, c.cfg.DefaultTimeout)
+	var res any
+	err = retry.do(ctx, true, func() error {
+		// default timeout in here in addition to potential context timeout
+		var watchErr error
+		_, res, watchErr = sentinel.Watch(ctx, c.cfg.PollInterval, c.cfg.DefaultTimeout)
+		return watchErr
+	})
 	if err != nil {
 		return nil, wrapErrf(err, "error connecting: host=%s port=%d, httpPath=%s", c.cfg.Host, c.cfg.Port, c.cfg.HTTPPath)
 	}
@@ -67,25 +97,44 @@ func (c *connector) Connect(ctx context.Context) (driver.Conn, error) {
 
 	log.Info().Msgf("connect: host=%s port=%d httpPath=%s", c.cfg.Host, c.cfg.Port, c.cfg.HTTPPath)
 
-	for k, v := range c.cfg.SessionParams {
-		setStmt := fmt.Sprintf("SET `%s` = `%s`;", k, v)
-		_, err := conn.ExecContext(ctx, setStmt, []driver.NamedValue{})
+	rconn := &retryingConn{conn: conn, retry: retry}
+
+	for _, setStmt := range buildSessionParamStmts(c.cfg.SessionParams, c.cfg.ConnParams) {
+		_, err := rconn.ExecContext(ctx, setStmt, []driver.NamedValue{})
 		if err != nil {
 			return nil, err
 		}
-		log.Info().Msgf("set session parameter: param=%s value=%s", k, v)
+		log.Info().Msgf("set session parameter: stmt=%s", setStmt)
 	}
-	return conn, nil
+	return rconn, nil
 }
 
 func (c *connector) Driver() driver.Driver {
 	return &databricksDriver{}
 }
 
-var _ driver.Connector = (*connector)(nil)
+// Close closes the configured Authenticator when it implements io.Closer.
+// None of the authenticators built by this package do, so their cached
+// tokens are kept, and the connector's HTTP transport and its idle
+// connections are not closed. It is called automatically when the owning
+// sql.DB is closed.
+func (c *connector) Close() error {
+	if closer, ok := c.cfg.Authenticator.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
+
+var (
+	_ driver.Connector = (*connector)(nil)
+	_ io.Closer        = (*connector)(nil)
+)
 
 type connOption func(*config.Config)
 
+// NewConnector builds a driver.Connector from functional options. Prefer
+// this when connecting without a DSN string; for sql.Open("databricks", dsn)
+// the driver's OpenConnector is used instead and parses the DSN once.
 func NewConnector(options ...connOption) (driver.Connector, error) {
 	// config with default options
 	cfg := config.WithDefaults()
@@ -93,9 +142,11 @@ func NewConnector(options ...connOption) (driver.Connector, error) {
 	for _, opt := range options {
 		opt(cfg)
 	}
-	// validate config?
+	if cfg.ConnParamsErr != nil {
+		return nil, cfg.ConnParamsErr
+	}
 
-	return &connector{cfg}, nil
+	return &connector{cfg: cfg}, nil
 }
 
 func WithServerHostname(host string) connOption {
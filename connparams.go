@@ -0,0 +1,98 @@
+package dbsql
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/databricks/databricks-sql-go/internal/config"
+	"github.com/pkg/errors"
+)
+
+// allowedConnParams is the set of session parameters this driver will
+// forward to the server via SET, guarding against typos and against the
+// backtick-injection risk of building SET statements from arbitrary keys.
+var allowedConnParams = map[string]bool{
+	"ansi_mode":         true,
+	"timezone":          true,
+	"use_cached_result": true,
+	"statement_timeout": true,
+	"query_tags":        true,
+}
+
+// WithConnParams sets session parameters applied once when a connection is
+// opened, one SET per entry (the Thrift ExecuteStatement endpoint accepts a
+// single SQL statement per call, so these can't be batched into one
+// round-trip). Values may be bool, int/int64, string or time.Duration; keys
+// are validated against an allow-list at NewConnector time so a typo or
+// unsupported parameter fails fast instead of after a live connect.
+func WithConnParams(params map[string]any) connOption {
+	return func(c *config.Config) {
+		quoted, err := quoteConnParams(params)
+		if err != nil {
+			c.ConnParamsErr = err
+			return
+		}
+		c.ConnParams = quoted
+	}
+}
+
+// quoteConnParams validates param names against allowedConnParams and
+// renders each value as a SQL literal safe to splice into a SET statement.
+func quoteConnParams(params map[string]any) (map[string]string, error) {
+	quoted := make(map[string]string, len(params))
+	for k, v := range params {
+		if !allowedConnParams[k] {
+			return nil, errors.Errorf("databricks: %q is not a recognized connection parameter", k)
+		}
+		literal, err := quoteConnParamValue(v)
+		if err != nil {
+			return nil, errors.Wrapf(err, "databricks: invalid value for connection parameter %q", k)
+		}
+		quoted[k] = literal
+	}
+	return quoted, nil
+}
+
+func quoteConnParamValue(v any) (string, error) {
+	switch val := v.(type) {
+	case bool:
+		if val {
+			return "TRUE", nil
+		}
+		return "FALSE", nil
+	case int:
+		return strconv.Itoa(val), nil
+	case int64:
+		return strconv.FormatInt(val, 10), nil
+	case time.Duration:
+		return quoteSQLString(val.String()), nil
+	case string:
+		return quoteSQLString(val), nil
+	default:
+		return "", errors.Errorf("unsupported type %T", v)
+	}
+}
+
+// quoteSQLString single-quotes s for use as a SQL string literal, escaping
+// embedded single quotes by doubling them.
+func quoteSQLString(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+// buildSessionParamStmts renders one SET statement per entry in
+// sessionParams (legacy, string-valued) and connParams (already-quoted
+// literals from WithConnParams). Each must still be sent as its own
+// ExecuteStatement call — HiveServer2-compatible Thrift endpoints, which
+// Databricks SQL's is, execute exactly one SQL statement per call.
+func buildSessionParamStmts(sessionParams map[string]string, connParams map[string]string) []string {
+	stmts := make([]string, 0, len(sessionParams)+len(connParams))
+	for k, v := range sessionParams {
+		stmts = append(stmts, fmt.Sprintf("SET `%s` = %s;", k, quoteSQLString(v)))
+	}
+	for k, v := range connParams {
+		stmts = append(stmts, fmt.Sprintf("SET `%s` = %s;", k, v))
+	}
+	return stmts
+}
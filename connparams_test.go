@@ -0,0 +1,106 @@
+package dbsql
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestQuoteSQLString(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"plain", "'plain'"},
+		{"", "''"},
+		{"o'brien", "'o''brien'"},
+		{"`; DROP TABLE x; --", "'`; DROP TABLE x; --'"},
+	}
+	for _, tt := range tests {
+		if got := quoteSQLString(tt.in); got != tt.want {
+			t.Errorf("quoteSQLString(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestQuoteConnParamValue(t *testing.T) {
+	tests := []struct {
+		name string
+		in   any
+		want string
+	}{
+		{"bool true", true, "TRUE"},
+		{"bool false", false, "FALSE"},
+		{"int", 42, "42"},
+		{"int64", int64(-7), "-7"},
+		{"duration", 90 * time.Second, "'1m30s'"},
+		{"string escapes quotes", "a'b", "'a''b'"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := quoteConnParamValue(tt.in)
+			if err != nil {
+				t.Fatalf("quoteConnParamValue(%v) returned error: %v", tt.in, err)
+			}
+			if got != tt.want {
+				t.Errorf("quoteConnParamValue(%v) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestQuoteConnParamValueUnsupportedType(t *testing.T) {
+	if _, err := quoteConnParamValue(3.14); err == nil {
+		t.Fatal("expected an error for an unsupported type, got nil")
+	}
+}
+
+func TestQuoteConnParamsRejectsUnknownKey(t *testing.T) {
+	_, err := quoteConnParams(map[string]any{"not_a_real_param": "x"})
+	if err == nil {
+		t.Fatal("expected an error for a key not on the allow-list, got nil")
+	}
+}
+
+// TestQuoteConnParamsNeutralizesBacktickInjection guards against the original
+// fmt.Sprintf("SET `%s` = `%s`;", k, v) being vulnerable to a value that
+// closes the backtick-quoted literal early and appends extra SQL: the whole
+// value, backticks included, must end up inside one single-quoted literal.
+func TestQuoteConnParamsNeutralizesBacktickInjection(t *testing.T) {
+	quoted, err := quoteConnParams(map[string]any{
+		"timezone": "UTC`; SET `ansi_mode` = `false",
+	})
+	if err != nil {
+		t.Fatalf("quoteConnParams returned error: %v", err)
+	}
+	literal := quoted["timezone"]
+	if want := "'UTC`; SET `ansi_mode` = `false'"; literal != want {
+		t.Errorf("quoted value = %q, want the whole value in one single-quoted literal %q", literal, want)
+	}
+	if !strings.HasPrefix(literal, "'") || !strings.HasSuffix(literal, "'") {
+		t.Errorf("quoted value %q must be a single-quoted SQL string literal", literal)
+	}
+}
+
+func TestBuildSessionParamStmts(t *testing.T) {
+	if stmts := buildSessionParamStmts(nil, nil); len(stmts) != 0 {
+		t.Errorf("expected no statements for no params, got %v", stmts)
+	}
+
+	stmts := buildSessionParamStmts(map[string]string{"timezone": "UTC"}, map[string]string{"ansi_mode": "TRUE"})
+	if len(stmts) != 2 {
+		t.Fatalf("expected one statement per param, got %d: %v", len(stmts), stmts)
+	}
+	for _, stmt := range stmts {
+		if !strings.HasSuffix(stmt, ";") {
+			t.Errorf("expected statement to end with a semicolon, got %q", stmt)
+		}
+	}
+	joined := strings.Join(stmts, " ")
+	if !strings.Contains(joined, "SET `timezone` = 'UTC';") {
+		t.Errorf("expected a statement for the session param SET, got %v", stmts)
+	}
+	if !strings.Contains(joined, "SET `ansi_mode` = TRUE;") {
+		t.Errorf("expected a statement for the conn param SET, got %v", stmts)
+	}
+}
@@ -0,0 +1,50 @@
+package dbsql
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+
+	"github.com/databricks/databricks-sql-go/internal/config"
+)
+
+func init() {
+	sql.Register("databricks", &databricksDriver{})
+}
+
+// databricksDriver is the database/sql driver registered under the name
+// "databricks".
+type databricksDriver struct{}
+
+// Open parses dsn and connects in a single step. Most callers should use
+// sql.Open, which prefers OpenConnector (via DriverContext) so the DSN is
+// only parsed once no matter how many connections the pool opens.
+func (d databricksDriver) Open(dsn string) (driver.Conn, error) {
+	c, err := d.OpenConnector(dsn)
+	if err != nil {
+		return nil, err
+	}
+	return c.Connect(context.Background())
+}
+
+// OpenConnector parses dsn into a *config.Config once and returns a
+// *connector backed by it, mirroring the vitessdriver DriverContext
+// refactor so database/sql doesn't reparse the DSN on every pool checkout.
+func (d databricksDriver) OpenConnector(dsn string) (driver.Connector, error) {
+	var cfg *config.Config
+	var err error
+	if isUnixSocketDSN(dsn) {
+		cfg, err = parseUnixSocketDSN(dsn)
+	} else {
+		cfg, err = config.ParseDSN(dsn)
+	}
+	if err != nil {
+		return nil, wrapErrf(err, "error parsing dsn")
+	}
+	return &connector{cfg: cfg}, nil
+}
+
+var (
+	_ driver.Driver        = databricksDriver{}
+	_ driver.DriverContext = databricksDriver{}
+)
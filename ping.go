@@ -0,0 +1,42 @@
+package dbsql
+
+import (
+	"context"
+	"database/sql/driver"
+
+	"github.com/databricks/databricks-sql-go/internal/cli_service"
+	"github.com/databricks/databricks-sql-go/internal/config"
+)
+
+// Ping issues a lightweight GetInfo RPC against the existing Thrift session
+// rather than opening a brand new session, which is what database/sql falls
+// back to for health checks without a driver.Pinger. A flaky ping is retried
+// with backoff (see WithHealthCheckRetries) before the caller's pool evicts
+// the connection.
+func (c *conn) Ping(ctx context.Context) error {
+	retry := retryPolicyFromConfig(c.cfg)
+	retry.maxRetries = c.cfg.HealthCheckRetries
+
+	err := retry.do(ctx, true, func() error {
+		_, err := c.client.GetInfo(ctx, &cli_service.TGetInfoReq{
+			SessionHandle: c.session.SessionHandle,
+			InfoType:      cli_service.TGetInfoType_CLI_SERVER_NAME,
+		})
+		return err
+	})
+	if err != nil {
+		return wrapErr(err, "error pinging databricks")
+	}
+	return nil
+}
+
+var _ driver.Pinger = (*conn)(nil)
+
+// WithHealthCheckRetries sets how many additional attempts sql.DB.PingContext
+// makes, with the same backoff as WithRetryBackoff, before reporting the
+// connection unhealthy. Defaults to 0 (no retry).
+func WithHealthCheckRetries(n int) connOption {
+	return func(c *config.Config) {
+		c.HealthCheckRetries = n
+	}
+}
@@ -0,0 +1,152 @@
+package dbsql
+
+import (
+	"context"
+	"math/rand"
+	"net"
+	"time"
+
+	"github.com/databricks/databricks-sql-go/internal/config"
+)
+
+// RetryableError can be implemented by errors returned from the Thrift/HTTP
+// layer to explicitly mark themselves as safe to retry, overriding the
+// default classification in isRetryableError.
+type RetryableError interface {
+	Retryable() bool
+}
+
+// retryPolicy controls how Connect, OpenSession and statement execution are
+// retried on transient failures.
+type retryPolicy struct {
+	maxRetries         int
+	backoffMin         time.Duration
+	backoffMax         time.Duration
+	isRetryable        func(error) bool
+	retryNonIdempotent bool
+}
+
+func retryPolicyFromConfig(cfg *config.Config) retryPolicy {
+	p := retryPolicy{
+		maxRetries:         cfg.MaxRetries,
+		backoffMin:         cfg.RetryBackoffMin,
+		backoffMax:         cfg.RetryBackoffMax,
+		isRetryable:        cfg.IsRetryableError,
+		retryNonIdempotent: cfg.RetryNonIdempotent,
+	}
+	if p.isRetryable == nil {
+		p.isRetryable = isRetryableError
+	}
+	if p.backoffMin <= 0 {
+		p.backoffMin = 500 * time.Millisecond
+	}
+	if p.backoffMax <= 0 {
+		p.backoffMax = 30 * time.Second
+	}
+	return p
+}
+
+// do runs fn, retrying up to p.maxRetries times with exponential backoff and
+// full jitter while the error is classified as retryable. idempotent should
+// be false for statements that mutate data; non-idempotent operations are
+// only retried when retryNonIdempotent is set.
+func (p retryPolicy) do(ctx context.Context, idempotent bool, fn func() error) error {
+	if p.maxRetries <= 0 || (!idempotent && !p.retryNonIdempotent) {
+		return fn()
+	}
+
+	var err error
+	backoff := p.backoffMin
+	for attempt := 0; attempt <= p.maxRetries; attempt++ {
+		err = fn()
+		if err == nil {
+			return nil
+		}
+		// context.DeadlineExceeded only stops the loop when it's the outer
+		// context, not an error surfaced from a single attempt's sub-context.
+		if ctx.Err() != nil {
+			return err
+		}
+		if attempt == p.maxRetries || !p.isRetryable(err) {
+			return err
+		}
+
+		wait := time.Duration(rand.Int63n(int64(backoff)))
+		select {
+		case <-time.After(wait):
+		case <-ctx.Done():
+			return err
+		}
+		backoff *= 2
+		if backoff > p.backoffMax {
+			backoff = p.backoffMax
+		}
+	}
+	return err
+}
+
+// isRetryableError applies the default retry classification: network
+// timeouts, HTTP 429/5xx status codes and errors the server explicitly marks
+// as retryable. A Retry-After header is not read; the wait before the next
+// attempt is always the jittered backoff in do.
+func isRetryableError(err error) bool {
+	if err == nil {
+		return false
+	}
+	if re, ok := err.(RetryableError); ok {
+		return re.Retryable()
+	}
+	if ne, ok := err.(net.Error); ok {
+		return ne.Timeout() || ne.Temporary() //nolint:staticcheck // Temporary is still the best signal Thrift gives us
+	}
+	if hse, ok := err.(httpStatusError); ok {
+		return hse.StatusCode() == 429 || hse.StatusCode() >= 500
+	}
+	return false
+}
+
+// httpStatusError is satisfied by HTTP transport errors that expose the
+// response status code, without this package needing to import the
+// specific HTTP client used by the Thrift transport.
+type httpStatusError interface {
+	StatusCode() int
+}
+
+// WithRetries enables the retry subsystem and sets the maximum number of
+// additional attempts made after the initial one for Connect, OpenSession
+// and the ExecContext/QueryContext calls of a connection. Fetches that the
+// returned Rows make after QueryContext has returned are not retried. A
+// value of 0 (the default) disables retries entirely.
+func WithRetries(n int) connOption {
+	return func(c *config.Config) {
+		c.MaxRetries = n
+	}
+}
+
+// WithRetryBackoff sets the exponential backoff bounds used between retry
+// attempts. Each wait is chosen with full jitter in [0, backoff).
+func WithRetryBackoff(min, max time.Duration) connOption {
+	return func(c *config.Config) {
+		c.RetryBackoffMin = min
+		c.RetryBackoffMax = max
+	}
+}
+
+// WithRetryableErrors overrides the default error classifier used to decide
+// whether a failure is safe to retry.
+func WithRetryableErrors(isRetryable func(error) bool) connOption {
+	return func(c *config.Config) {
+		c.IsRetryableError = isRetryable
+	}
+}
+
+// WithRetryNonIdempotent opts in to retrying statements that are not known
+// to be idempotent (i.e. anything not starting with SELECT, SHOW, DESCRIBE,
+// EXPLAIN, SET or USE; see isIdempotentStatement). Off by default
+// since retrying a partially applied INSERT/UPDATE/DELETE can duplicate
+// side effects.
+func WithRetryNonIdempotent(retry bool) connOption {
+	return func(c *config.Config) {
+		c.RetryNonIdempotent = retry
+	}
+}
@@ -0,0 +1,135 @@
+package dbsql
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+)
+
+type fakeNetError struct{ timeout bool }
+
+func (e fakeNetError) Error() string   { return "fake net error" }
+func (e fakeNetError) Timeout() bool   { return e.timeout }
+func (e fakeNetError) Temporary() bool { return e.timeout }
+
+var _ net.Error = fakeNetError{}
+
+func TestRetryPolicyDoRetriesRetryableErrors(t *testing.T) {
+	p := retryPolicy{
+		maxRetries:  3,
+		backoffMin:  time.Millisecond,
+		backoffMax:  time.Millisecond,
+		isRetryable: func(error) bool { return true },
+	}
+
+	attempts := 0
+	err := p.do(context.Background(), true, func() error {
+		attempts++
+		if attempts < 3 {
+			return fakeNetError{timeout: true}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("expected success after retries, got error: %v", err)
+	}
+	if attempts != 3 {
+		t.Errorf("expected 3 attempts, got %d", attempts)
+	}
+}
+
+func TestRetryPolicyDoStopsOnNonRetryableError(t *testing.T) {
+	p := retryPolicy{
+		maxRetries:  5,
+		backoffMin:  time.Millisecond,
+		backoffMax:  time.Millisecond,
+		isRetryable: isRetryableError,
+	}
+
+	attempts := 0
+	wantErr := errors.New("permanent failure")
+	err := p.do(context.Background(), true, func() error {
+		attempts++
+		return wantErr
+	})
+	if !errors.Is(err, wantErr) {
+		t.Fatalf("expected the permanent error to be returned, got %v", err)
+	}
+	if attempts != 1 {
+		t.Errorf("expected exactly 1 attempt for a non-retryable error, got %d", attempts)
+	}
+}
+
+func TestRetryPolicyDoSkipsNonIdempotentUnlessOptedIn(t *testing.T) {
+	p := retryPolicy{
+		maxRetries:  5,
+		backoffMin:  time.Millisecond,
+		backoffMax:  time.Millisecond,
+		isRetryable: func(error) bool { return true },
+	}
+
+	attempts := 0
+	retryable := fakeNetError{timeout: true}
+	_ = p.do(context.Background(), false, func() error {
+		attempts++
+		return retryable
+	})
+	if attempts != 1 {
+		t.Errorf("expected no retries for a non-idempotent statement by default, got %d attempts", attempts)
+	}
+
+	p.retryNonIdempotent = true
+	attempts = 0
+	_ = p.do(context.Background(), false, func() error {
+		attempts++
+		if attempts < 2 {
+			return retryable
+		}
+		return nil
+	})
+	if attempts != 2 {
+		t.Errorf("expected retries once WithRetryNonIdempotent is set, got %d attempts", attempts)
+	}
+}
+
+func TestRetryPolicyDoRespectsContextCancellation(t *testing.T) {
+	p := retryPolicy{
+		maxRetries:  10,
+		backoffMin:  50 * time.Millisecond,
+		backoffMax:  50 * time.Millisecond,
+		isRetryable: func(error) bool { return true },
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	attempts := 0
+	done := make(chan error, 1)
+	go func() {
+		done <- p.do(ctx, true, func() error {
+			attempts++
+			if attempts == 1 {
+				cancel()
+			}
+			return fakeNetError{timeout: true}
+		})
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("retry loop did not stop promptly after context cancellation")
+	}
+}
+
+func TestIsRetryableError(t *testing.T) {
+	if isRetryableError(nil) {
+		t.Error("nil error should not be retryable")
+	}
+	if !isRetryableError(fakeNetError{timeout: true}) {
+		t.Error("a timeout net.Error should be retryable")
+	}
+	if isRetryableError(errors.New("boom")) {
+		t.Error("a plain error should not be retryable by default")
+	}
+}
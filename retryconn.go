@@ -0,0 +1,58 @@
+package dbsql
+
+import (
+	"context"
+	"database/sql/driver"
+	"strings"
+)
+
+// retryingConn decorates a *conn, applying the configured retryPolicy around
+// ExecContext/QueryContext so transient failures during those calls are
+// retried the same way Connect's InitThriftClient/OpenSession calls already
+// are. The driver.Rows returned by QueryContext is not wrapped, so fetches
+// it makes afterwards are not retried. Statements are only retried when classified
+// as idempotent (see isIdempotentStatement) unless WithRetryNonIdempotent is
+// set, since retrying a partially applied mutation can duplicate side
+// effects.
+type retryingConn struct {
+	*conn
+	retry retryPolicy
+}
+
+// isIdempotentStatement reports whether query is safe to retry by default:
+// read-only or session/metadata statements, as opposed to statements that
+// mutate data.
+func isIdempotentStatement(query string) bool {
+	q := strings.TrimSpace(strings.ToLower(query))
+	for _, prefix := range []string{"select", "show", "describe", "explain", "set", "use"} {
+		if strings.HasPrefix(q, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *retryingConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	var res driver.Result
+	err := c.retry.do(ctx, isIdempotentStatement(query), func() error {
+		var execErr error
+		res, execErr = c.conn.ExecContext(ctx, query, args)
+		return execErr
+	})
+	return res, err
+}
+
+func (c *retryingConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	var rows driver.Rows
+	err := c.retry.do(ctx, isIdempotentStatement(query), func() error {
+		var queryErr error
+		rows, queryErr = c.conn.QueryContext(ctx, query, args)
+		return queryErr
+	})
+	return rows, err
+}
+
+var (
+	_ driver.ExecerContext  = (*retryingConn)(nil)
+	_ driver.QueryerContext = (*retryingConn)(nil)
+)
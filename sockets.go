@@ -0,0 +1,89 @@
+package dbsql
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/databricks/databricks-sql-go/internal/config"
+	"github.com/pkg/errors"
+)
+
+// unixSocketScheme is the DSN prefix recognized for connecting over a Unix
+// domain socket, e.g. unix:///var/run/databricks.sock.
+const unixSocketScheme = "unix://"
+
+// WithUnixSocket routes the Thrift-over-HTTP traffic through a Unix domain
+// socket at path instead of a TCP host/port, e.g. to reach a local SQL
+// gateway or proxy. It implies a dialer and is mutually exclusive with
+// WithDialer.
+func WithUnixSocket(path string) connOption {
+	return func(c *config.Config) {
+		c.UnixSocketPath = path
+	}
+}
+
+// WithDialer overrides the net.Conn used to establish the underlying HTTP
+// connection, e.g. to route through a SOCKS/HTTP proxy or a test double.
+// It is ignored when WithUnixSocket is also set.
+func WithDialer(dial func(ctx context.Context, network, addr string) (net.Conn, error)) connOption {
+	return func(c *config.Config) {
+		c.DialContext = dial
+	}
+}
+
+// WithHTTPTransport overrides the http.RoundTripper used for all Thrift
+// requests, e.g. to install mutual TLS client certificates or a custom
+// proxy configuration. When the transport is an *http.Transport,
+// WithUnixSocket/WithDialer still replace its DialContext (on a clone); any
+// other RoundTripper does its own dialing and they are ignored.
+func WithHTTPTransport(transport http.RoundTripper) connOption {
+	return func(c *config.Config) {
+		c.HTTPTransport = transport
+	}
+}
+
+// isUnixSocketDSN reports whether dsn names a Unix domain socket target
+// rather than a host/port URL.
+func isUnixSocketDSN(dsn string) bool {
+	return strings.HasPrefix(dsn, unixSocketScheme)
+}
+
+// placeholderAuthority stands in for the host:port a normal databricks://
+// DSN requires, so the rest of config.ParseDSN's validation passes; it's
+// discarded once parsing is done since the socket path takes its place.
+const placeholderAuthority = "localhost:443"
+
+// parseUnixSocketDSN builds a config.Config for a unix:// DSN. The DSN's
+// path is taken as the socket path and is not passed on, so it does not set
+// httpPath. The rest (user info, query string) is parsed by substituting a
+// placeholder authority and an empty path into a databricks:// DSN and
+// running it through config.ParseDSN, so a unix socket DSN gets the same
+// query-string handling as a normal one; the placeholder host/port is then
+// discarded in favor of the socket path.
+func parseUnixSocketDSN(dsn string) (*config.Config, error) {
+	u, err := url.Parse(dsn)
+	if err != nil {
+		return nil, errors.Wrapf(err, "databricks: invalid unix socket dsn %q", dsn)
+	}
+	if u.Path == "" {
+		return nil, errors.Errorf("databricks: unix socket dsn %q is missing a path", dsn)
+	}
+	socketPath := u.Path
+
+	placeholder := *u
+	placeholder.Scheme = "databricks"
+	placeholder.Host = placeholderAuthority
+	placeholder.Path = ""
+
+	cfg, err := config.ParseDSN(placeholder.String())
+	if err != nil {
+		return nil, errors.Wrapf(err, "databricks: invalid unix socket dsn %q", dsn)
+	}
+	cfg.Host = ""
+	cfg.Port = 0
+	cfg.UnixSocketPath = socketPath
+	return cfg, nil
+}
@@ -0,0 +1,35 @@
+package dbsql
+
+import "testing"
+
+func TestIsUnixSocketDSN(t *testing.T) {
+	tests := []struct {
+		dsn  string
+		want bool
+	}{
+		{"unix:///var/run/databricks.sock", true},
+		{"databricks://token@host:443/path", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		if got := isUnixSocketDSN(tt.dsn); got != tt.want {
+			t.Errorf("isUnixSocketDSN(%q) = %v, want %v", tt.dsn, got, tt.want)
+		}
+	}
+}
+
+func TestParseUnixSocketDSN(t *testing.T) {
+	cfg, err := parseUnixSocketDSN("unix:///var/run/databricks.sock")
+	if err != nil {
+		t.Fatalf("parseUnixSocketDSN returned error: %v", err)
+	}
+	if cfg.UnixSocketPath != "/var/run/databricks.sock" {
+		t.Errorf("UnixSocketPath = %q, want %q", cfg.UnixSocketPath, "/var/run/databricks.sock")
+	}
+}
+
+func TestParseUnixSocketDSNRejectsMissingPath(t *testing.T) {
+	if _, err := parseUnixSocketDSN("unix://"); err == nil {
+		t.Fatal("expected an error for a unix dsn with no path, got nil")
+	}
+}
@@ -0,0 +1,70 @@
+package dbsql
+
+import (
+	"context"
+	"net"
+	"net/http"
+
+	"github.com/databricks/databricks-sql-go/internal/config"
+)
+
+// authenticatingTransport injects a bearer token obtained from auth on
+// every request, relying on auth's own caching (see cachingAuthenticator)
+// to avoid fetching a new token per request.
+type authenticatingTransport struct {
+	base http.RoundTripper
+	auth Authenticator
+}
+
+func (t *authenticatingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	token, _, err := t.auth.Token(req.Context())
+	if err != nil {
+		return nil, wrapErr(err, "error obtaining auth token")
+	}
+	req = req.Clone(req.Context())
+	req.Header.Set("Authorization", "Bearer "+token)
+	return t.base.RoundTrip(req)
+}
+
+// buildHTTPTransport assembles the final http.RoundTripper used for Thrift
+// requests: cfg.HTTPTransport (or http.DefaultTransport) with its dial
+// behavior overridden per WithUnixSocket/WithDialer when it's a plain
+// *http.Transport, topped with the authenticating layer when an
+// Authenticator is configured. This is what client.InitThriftClient picks
+// up via cfg.HTTPTransport, so configuring WithUnixSocket/WithDialer/
+// WithOAuth*/WithAzureAD/WithTokenSource actually changes the connection
+// instead of being a no-op.
+func buildHTTPTransport(cfg *config.Config) http.RoundTripper {
+	base := cfg.HTTPTransport
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	if dial := dialContextFor(cfg); dial != nil {
+		if rt, ok := base.(*http.Transport); ok {
+			rt = rt.Clone()
+			rt.DialContext = dial
+			base = rt
+		}
+		// A caller-supplied RoundTripper that isn't *http.Transport owns its
+		// own dialing; WithUnixSocket/WithDialer only apply to the default
+		// transport in that case.
+	}
+
+	if cfg.Authenticator == nil {
+		return base
+	}
+	return &authenticatingTransport{base: base, auth: cfg.Authenticator}
+}
+
+// dialContextFor returns the dial function implied by WithUnixSocket (which
+// takes precedence) or WithDialer, or nil if neither was configured.
+func dialContextFor(cfg *config.Config) func(ctx context.Context, network, addr string) (net.Conn, error) {
+	if cfg.UnixSocketPath != "" {
+		return func(ctx context.Context, _, _ string) (net.Conn, error) {
+			var d net.Dialer
+			return d.DialContext(ctx, "unix", cfg.UnixSocketPath)
+		}
+	}
+	return cfg.DialContext
+}